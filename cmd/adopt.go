@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/benvh/ricer/ricer"
+	"github.com/spf13/cobra"
+)
+
+var adoptCmd = &cobra.Command{
+	Use:   "adopt <file>",
+	Short: "Import an existing dotfile into the templates directory",
+	Long: `Import an existing dotfile into the templates directory.
+
+The file at <file> is copied into the templates directory as a new
+template and its path is registered as that template's output, so a
+subsequent "ricer apply" renders it straight back.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tmplDir, err := ricer.TemplatesDirectory()
+		if err != nil {
+			return err
+		}
+
+		if err := ricer.Adopt(tmplDir, args[0]); err != nil {
+			return err
+		}
+
+		fmt.Printf("Adopted %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(adoptCmd)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/benvh/ricer/ricer"
+	"github.com/spf13/cobra"
+)
+
+var watchFlag bool
+
+var applyCmd = &cobra.Command{
+	Use:   "apply [template...]",
+	Short: "Render templates to their configured outputs",
+	Long: `Render templates to their configured outputs.
+
+With no arguments every file under the templates directory is rendered (or,
+for non-templates and keep_patterns matches, copied through). Pass one or
+more names (a file's path relative to the templates directory, with any
+.tmpl extension stripped) to apply only those.
+
+Pass --profile all to apply every profile declared in the configuration,
+one after another, instead of just the active one.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if watchFlag && profileFlag == "all" {
+			return fmt.Errorf("--watch cannot be combined with --profile all: watch tracks a single active profile")
+		}
+
+		tmplDir, err := ricer.TemplatesDirectory()
+		if err != nil {
+			return err
+		}
+
+		files, err := ricer.Discover(tmplDir, args...)
+		if err != nil {
+			return err
+		}
+
+		profiles := []string{ricer.Profile()}
+		if profileFlag == "all" {
+			if all := ricer.Profiles(); len(all) > 0 {
+				profiles = all
+			}
+		}
+
+		for _, profile := range profiles {
+			ricer.SetProfile(profile)
+			ricer.Apply(files)
+		}
+
+		if watchFlag {
+			return ricer.Watch(tmplDir)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	applyCmd.Flags().BoolVarP(&watchFlag, "watch", "w", false, "keep running and re-render templates as they (or the config) change")
+	rootCmd.AddCommand(applyCmd)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/benvh/ricer/ricer"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [template...]",
+	Short: "Show what apply would change without writing any files",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tmplDir, err := ricer.TemplatesDirectory()
+		if err != nil {
+			return err
+		}
+
+		files, err := ricer.Discover(tmplDir, args...)
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			out, err := ricer.Diff(file)
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+
+			fmt.Print(out)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/benvh/ricer/ricer"
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Scaffold ~/.config/ricer/config.yaml and templates/",
+	// No configuration exists yet, so skip the root command's
+	// PersistentPreRunE, which would otherwise fail to read it.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := ricer.Init(); err != nil {
+			return err
+		}
+
+		configHome, err := ricer.ConfigHomeDirectory()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Scaffolded %s\n", configHome)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+}
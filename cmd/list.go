@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/benvh/ricer/ricer"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured templates, their sources and outputs",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tmplDir, err := ricer.TemplatesDirectory()
+		if err != nil {
+			return err
+		}
+
+		infos, err := ricer.List(tmplDir)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		defer w.Flush()
+
+		fmt.Fprintln(w, "NAME\tSOURCE\tOUTPUT")
+		for _, info := range infos {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", info.Name, info.Source, info.Output)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+}
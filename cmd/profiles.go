@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/benvh/ricer/ricer"
+	"github.com/spf13/cobra"
+)
+
+var profilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "Inspect the profiles declared in the configuration",
+}
+
+var profilesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every profile declared under the top-level `profiles` key",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, name := range ricer.Profiles() {
+			fmt.Println(name)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	profilesCmd.AddCommand(profilesListCmd)
+	rootCmd.AddCommand(profilesCmd)
+}
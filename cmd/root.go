@@ -0,0 +1,78 @@
+/*
+Ricer generates configuration files based on templates.
+Copyright (C) 2016  Kristof Vannotten
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package cmd implements ricer's command-line interface.
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/benvh/ricer/ricer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Version and BuildDate are set from main via -ldflags at build time.
+	Version   string
+	BuildDate string
+
+	configFile  string
+	profileFlag string
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "ricer",
+	Short: "ricer generates configuration files based on templates",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := ricer.ParseConfiguration(configFile); err != nil {
+			return err
+		}
+
+		if profileFlag == "" {
+			profileFlag = os.Getenv("RICER_PROFILE")
+		}
+
+		// "all" means "iterate over every declared profile", which only
+		// apply knows how to do; every other command needs one active
+		// profile (or none).
+		if profileFlag == "all" && cmd.Name() != "apply" {
+			return fmt.Errorf("--profile all is only supported by apply")
+		}
+		if profileFlag != "all" {
+			ricer.SetProfile(profileFlag)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "(optional) the configuration file to use")
+	rootCmd.PersistentFlags().StringVarP(&profileFlag, "profile", "p", "", "the profile to apply overlays from, or \"all\" to run every declared profile in turn (default from $RICER_PROFILE)")
+}
+
+// Execute runs the root command and exits the process on error.
+func Execute() {
+	rootCmd.Version = fmt.Sprintf("%s (built %s)", Version, BuildDate)
+
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,72 @@
+package ricer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Adopt imports the dotfile at destPath into tmplDir as a new template and
+// registers its output in the viper configuration, so that a subsequent
+// `ricer apply` renders it back to destPath.
+func Adopt(tmplDir, destPath string) error {
+	absDest, err := filepath.Abs(destPath)
+	if err != nil {
+		return err
+	}
+
+	contents, err := os.ReadFile(absDest)
+	if err != nil {
+		return fmt.Errorf("Could not read %s: %s", absDest, err)
+	}
+
+	// Keyed on the parent directory's basename plus the filename, not just
+	// the filename, so that adopting two dotfiles that share a basename
+	// from different directories (e.g. ~/.config/i3/config and
+	// ~/.config/sway/config) land as distinct templates ("i3/config",
+	// "sway/config") instead of one clobbering the other's template file
+	// and output registration.
+	tmplName := filepath.ToSlash(filepath.Join(filepath.Base(filepath.Dir(absDest)), filepath.Base(absDest)))
+
+	if existing := templateString(tmplName, "output"); existing != "" && existing != absDest {
+		return fmt.Errorf("%s is already adopted with output %s; refusing to overwrite it with %s", tmplName, existing, absDest)
+	}
+
+	tmplFile := filepath.Join(tmplDir, filepath.FromSlash(tmplName)+".tmpl")
+	if err := os.MkdirAll(filepath.Dir(tmplFile), os.ModePerm); err != nil {
+		return fmt.Errorf("Could not create %s: %s", filepath.Dir(tmplFile), err)
+	}
+
+	if err := os.WriteFile(tmplFile, contents, 0644); err != nil {
+		return fmt.Errorf("Could not write %s: %s", tmplFile, err)
+	}
+
+	// Merged as a nested map, not built as a dotted "tmplName.output"
+	// string: tmplName routinely contains literal dots of its own (e.g.
+	// "bashrc.conf") that viper's own dotted-path setters would otherwise
+	// misread as further nesting.
+	update := nestedMap(strings.Split(tmplName, "/"), map[string]interface{}{"output": absDest})
+	if err := viper.MergeConfigMap(update); err != nil {
+		return fmt.Errorf("Could not update configuration: %s", err)
+	}
+
+	if err := viper.WriteConfig(); err != nil {
+		return fmt.Errorf("Could not update configuration: %s", err)
+	}
+
+	return nil
+}
+
+// nestedMap wraps leaf under parts, one level per part, e.g.
+// nestedMap([]string{"i3", "config"}, leaf) is
+// map[string]interface{}{"i3": map[string]interface{}{"config": leaf}}.
+func nestedMap(parts []string, leaf map[string]interface{}) map[string]interface{} {
+	m := leaf
+	for i := len(parts) - 1; i >= 0; i-- {
+		m = map[string]interface{}{parts[i]: m}
+	}
+	return m
+}
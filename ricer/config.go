@@ -0,0 +1,92 @@
+package ricer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// templateConfig returns the configuration section for the file whose
+// configuration key is relPath (its path relative to the templates
+// directory, with any ".tmpl" stripped — e.g. "nvim/init.lua"), with the
+// active profile's overlay, if any, shallow-merged on top.
+//
+// The base lookup walks viper's settings one path component at a time
+// instead of handing viper a single dotted string, because relPath
+// routinely contains literal dots of its own (file extensions) that
+// viper's "." key delimiter would otherwise misinterpret as further
+// nesting. viper.Get on a single, undotted key is unaffected by this and
+// returns that section exactly as parsed; viper.AllSettings(), by
+// contrast, rebuilds its result by rejoining and re-splitting every key on
+// ".", which would mangle a component like "init.lua" into two levels of
+// nesting instead of one.
+func templateConfig(relPath string) map[string]interface{} {
+	parts := strings.Split(filepath.ToSlash(relPath), "/")
+
+	top, _ := viper.Get(parts[0]).(map[string]interface{})
+	base := lookupRest(top, parts[1:])
+
+	return mergeConfig(base, profileConfig(relPath))
+}
+
+// lookupRest walks node one part at a time using plain map indexing (no
+// further viper calls, so embedded dots in later parts are never
+// reinterpreted).
+func lookupRest(node map[string]interface{}, parts []string) map[string]interface{} {
+	for _, part := range parts {
+		next, ok := node[part]
+		if !ok {
+			return nil
+		}
+
+		m, ok := next.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		node = m
+	}
+
+	return node
+}
+
+// mergeConfig shallow-merges overlay's keys on top of base, so e.g. a
+// profile's own `vars` entirely replaces the base `vars` rather than being
+// merged key-by-key into it.
+func mergeConfig(base, overlay map[string]interface{}) map[string]interface{} {
+	if overlay == nil {
+		return base
+	}
+
+	merged := make(map[string]interface{}, len(base)+len(overlay))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overlay {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+// templateString returns relPath's configuration value for key, or "" if
+// either is unset.
+func templateString(relPath, key string) string {
+	s, _ := templateConfig(relPath)[key].(string)
+	return s
+}
+
+func expandHome(p string) (string, error) {
+	if !strings.HasPrefix(p, "~") {
+		return p, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, strings.TrimPrefix(p, "~")), nil
+}
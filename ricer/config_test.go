@@ -0,0 +1,143 @@
+package ricer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestLookupRest(t *testing.T) {
+	tests := []struct {
+		name  string
+		node  map[string]interface{}
+		parts []string
+		want  map[string]interface{}
+	}{
+		{
+			name:  "empty parts returns node",
+			node:  map[string]interface{}{"vars": map[string]interface{}{"name": "world"}},
+			parts: nil,
+			want:  map[string]interface{}{"vars": map[string]interface{}{"name": "world"}},
+		},
+		{
+			name: "walks a literal dotted component as one level",
+			node: map[string]interface{}{
+				"init.lua": map[string]interface{}{"vars": map[string]interface{}{"name": "world"}},
+			},
+			parts: []string{"init.lua"},
+			want:  map[string]interface{}{"vars": map[string]interface{}{"name": "world"}},
+		},
+		{
+			name:  "missing key returns nil",
+			node:  map[string]interface{}{"vars": map[string]interface{}{}},
+			parts: []string{"nope"},
+			want:  nil,
+		},
+		{
+			name:  "non-map value returns nil",
+			node:  map[string]interface{}{"vars": "not a map"},
+			parts: []string{"vars"},
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lookupRest(tt.node, tt.parts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lookupRest(%v, %v) = %v, want %v", tt.node, tt.parts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    map[string]interface{}
+		overlay map[string]interface{}
+		want    map[string]interface{}
+	}{
+		{
+			name:    "nil overlay returns base unchanged",
+			base:    map[string]interface{}{"vars": map[string]interface{}{"name": "base"}},
+			overlay: nil,
+			want:    map[string]interface{}{"vars": map[string]interface{}{"name": "base"}},
+		},
+		{
+			name:    "overlay key shallow-replaces base key",
+			base:    map[string]interface{}{"vars": map[string]interface{}{"name": "base", "extra": "kept"}},
+			overlay: map[string]interface{}{"vars": map[string]interface{}{"name": "overlay"}},
+			want:    map[string]interface{}{"vars": map[string]interface{}{"name": "overlay"}},
+		},
+		{
+			name:    "overlay-only key is added",
+			base:    map[string]interface{}{"vars": map[string]interface{}{"name": "base"}},
+			overlay: map[string]interface{}{"output": "/tmp/out"},
+			want: map[string]interface{}{
+				"vars":   map[string]interface{}{"name": "base"},
+				"output": "/tmp/out",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeConfig(tt.base, tt.overlay)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeConfig(%v, %v) = %v, want %v", tt.base, tt.overlay, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateConfigProfileOverlay(t *testing.T) {
+	viper.Reset()
+	defer func() { viper.Reset(); SetProfile("") }()
+
+	config := map[string]interface{}{
+		"nvim": map[string]interface{}{
+			"init.lua": map[string]interface{}{
+				"vars": map[string]interface{}{"name": "base"},
+			},
+		},
+		"profiles": map[string]interface{}{
+			"laptop": map[string]interface{}{
+				"nvim": map[string]interface{}{
+					"init.lua": map[string]interface{}{
+						"vars":   map[string]interface{}{"name": "laptop"},
+						"output": "/laptop/init.lua",
+					},
+				},
+			},
+		},
+	}
+	if err := viper.MergeConfigMap(config); err != nil {
+		t.Fatalf("MergeConfigMap: %v", err)
+	}
+
+	SetProfile("")
+	got := templateConfig("nvim/init.lua")
+	want := map[string]interface{}{"vars": map[string]interface{}{"name": "base"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("no profile: templateConfig() = %v, want %v", got, want)
+	}
+
+	SetProfile("laptop")
+	got = templateConfig("nvim/init.lua")
+	want = map[string]interface{}{
+		"vars":   map[string]interface{}{"name": "laptop"},
+		"output": "/laptop/init.lua",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("laptop profile: templateConfig() = %v, want %v", got, want)
+	}
+
+	SetProfile("desktop")
+	got = templateConfig("nvim/init.lua")
+	want = map[string]interface{}{"vars": map[string]interface{}{"name": "base"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("undeclared profile: templateConfig() = %v, want %v", got, want)
+	}
+}
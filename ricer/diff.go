@@ -0,0 +1,85 @@
+package ricer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// Diff renders (or, for a non-template f, just reads) the content f would
+// produce and returns a unified diff against the current contents of its
+// configured output file, without writing anything to disk. It returns an
+// empty string when that content already matches what's on disk.
+func Diff(f File) (string, error) {
+	var rendered []byte
+	var outputFile string
+
+	if f.Template {
+		buf, out, err := renderTemplate(f)
+		if err != nil {
+			return "", err
+		}
+		rendered, outputFile = buf.Bytes(), out
+	} else {
+		var err error
+		if outputFile, err = OutputPath(f.RelPath); err != nil {
+			return "", err
+		}
+		if rendered, err = os.ReadFile(f.Source); err != nil {
+			return "", fmt.Errorf("Could not read %s: %s", f.Source, err)
+		}
+	}
+
+	existing, err := os.ReadFile(outputFile)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("Could not read %s: %s", outputFile, err)
+	}
+
+	if bytes.Equal(existing, rendered) {
+		return "", nil
+	}
+
+	return unifiedDiff(outputFile, existing, rendered)
+}
+
+// unifiedDiff shells out to the system `diff` to produce a unified diff
+// between existing and rendered, labelling both sides with path.
+func unifiedDiff(path string, existing, rendered []byte) (string, error) {
+	existingFile, err := os.CreateTemp("", "ricer-diff-existing-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(existingFile.Name())
+	defer existingFile.Close()
+
+	renderedFile, err := os.CreateTemp("", "ricer-diff-rendered-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(renderedFile.Name())
+	defer renderedFile.Close()
+
+	if _, err := existingFile.Write(existing); err != nil {
+		return "", err
+	}
+	if _, err := renderedFile.Write(rendered); err != nil {
+		return "", err
+	}
+
+	out, err := exec.Command("diff", "-u",
+		"--label", path,
+		existingFile.Name(),
+		"--label", path,
+		renderedFile.Name(),
+	).Output()
+	// diff exits 1 when the inputs differ, which is the expected case here.
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		return string(out), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("Could not diff %s: %s", path, err)
+	}
+
+	return string(out), nil
+}
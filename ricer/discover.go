@@ -0,0 +1,133 @@
+package ricer
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// File is one file discovered under the templates directory.
+type File struct {
+	// Source is the file's absolute path.
+	Source string
+	// RelPath is Source's path relative to the templates directory, using
+	// "/" separators, with any ".tmpl" extension stripped. This doubles as
+	// both the file's configuration key and the default suffix of its
+	// output path.
+	RelPath string
+	// Template is true when Source should be parsed and executed as a
+	// template; false means it should be copied through verbatim.
+	Template bool
+}
+
+// Discover walks tmplDir recursively, skipping anything matched by the
+// top-level `skip_patterns` config and copying through anything matched by
+// `keep_patterns` (or that simply isn't a .tmpl file) rather than treating
+// it as a template. When names is non-empty, only files whose RelPath is
+// in names are returned.
+func Discover(tmplDir string, names ...string) ([]File, error) {
+	skip := viper.GetStringSlice("skip_patterns")
+	keep := viper.GetStringSlice("keep_patterns")
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	var files []File
+	err := filepath.WalkDir(tmplDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(tmplDir, p)
+		if err != nil {
+			return err
+		}
+		relSlash := filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if relSlash != "." && matchesAny(skip, relSlash) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if matchesAny(skip, relSlash) {
+			return nil
+		}
+
+		isTmpl := filepath.Ext(p) == ".tmpl"
+		template := isTmpl && !matchesAny(keep, relSlash)
+
+		outRel := relSlash
+		if isTmpl {
+			outRel = strings.TrimSuffix(relSlash, ".tmpl")
+		}
+
+		if len(wanted) > 0 && !wanted[outRel] {
+			return nil
+		}
+
+		files = append(files, File{Source: p, RelPath: outRel, Template: template})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// discoverDirs returns tmplDir and every subdirectory under it that isn't
+// matched by `skip_patterns`, for watchers that need to register each
+// directory individually.
+func discoverDirs(tmplDir string) ([]string, error) {
+	skip := viper.GetStringSlice("skip_patterns")
+
+	var dirs []string
+	err := filepath.WalkDir(tmplDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(tmplDir, p)
+		if err != nil {
+			return err
+		}
+		relSlash := filepath.ToSlash(rel)
+
+		if relSlash != "." && matchesAny(skip, relSlash) {
+			return filepath.SkipDir
+		}
+
+		dirs = append(dirs, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dirs, nil
+}
+
+// matchesAny reports whether relPath, or just its base name, matches any
+// of patterns.
+func matchesAny(patterns []string, relPath string) bool {
+	base := filepath.Base(relPath)
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, relPath); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, base); ok {
+			return true
+		}
+	}
+
+	return false
+}
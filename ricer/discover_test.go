@@ -0,0 +1,111 @@
+package ricer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestMatchesAny(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		relPath  string
+		want     bool
+	}{
+		{
+			name:     "no patterns never matches",
+			patterns: nil,
+			relPath:  "nvim/init.lua.tmpl",
+			want:     false,
+		},
+		{
+			name:     "matches the full relative path",
+			patterns: []string{"nvim/*.tmpl"},
+			relPath:  "nvim/init.lua.tmpl",
+			want:     true,
+		},
+		{
+			name:     "matches just the base name",
+			patterns: []string{"*.swp"},
+			relPath:  "nvim/.init.lua.swp",
+			want:     true,
+		},
+		{
+			name:     "no matching pattern",
+			patterns: []string{"*.swp", "nvim/*.bak"},
+			relPath:  "nvim/init.lua.tmpl",
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesAny(tt.patterns, tt.relPath); got != tt.want {
+				t.Errorf("matchesAny(%v, %q) = %v, want %v", tt.patterns, tt.relPath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	tmplDir := t.TempDir()
+	write := func(rel string) {
+		p := filepath.Join(tmplDir, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := os.WriteFile(p, []byte("content"), 0o644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	write("nvim/init.lua.tmpl")
+	write("nvim/icon.png")
+	write("nvim/init.lua.tmpl.swp")
+	write("bin/tool.tmpl")
+	write(".git/config")
+
+	viper.Set("skip_patterns", []string{"*.swp", ".git"})
+	viper.Set("keep_patterns", []string{"bin/*.tmpl"})
+
+	files, err := Discover(tmplDir)
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+
+	got := make(map[string]bool, len(files))
+	for _, f := range files {
+		got[f.RelPath] = f.Template
+	}
+
+	want := map[string]bool{
+		"nvim/init.lua": true,  // .tmpl stripped, rendered as a template
+		"nvim/icon.png": false, // not a .tmpl, copied through
+		"bin/tool":      false, // .tmpl stripped, but keep_patterns forces a copy
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover() RelPath/Template = %v, want %v", got, want)
+	}
+
+	// names filters down to the requested RelPaths only.
+	filtered, err := Discover(tmplDir, "nvim/init.lua")
+	if err != nil {
+		t.Fatalf("Discover with names: %v", err)
+	}
+	var rels []string
+	for _, f := range filtered {
+		rels = append(rels, f.RelPath)
+	}
+	sort.Strings(rels)
+	if want := []string{"nvim/init.lua"}; !reflect.DeepEqual(rels, want) {
+		t.Errorf("Discover(names) RelPaths = %v, want %v", rels, want)
+	}
+}
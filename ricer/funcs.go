@@ -0,0 +1,20 @@
+package ricer
+
+import "text/template"
+
+// templateFuncs exposes the same sources that `providers` entries draw from
+// directly inside a template, for one-off use that doesn't warrant a named
+// var: {{ env "HOME" }}, {{ include "~/.ssh/id_rsa.pub" }},
+// {{ exec "uname -r" }}, {{ secret "github-token" }}.
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"env":     EnvVar,
+		"include": IncludeFile,
+		"exec": func(command string) (string, error) {
+			return Exec(command, defaultExecTimeout)
+		},
+		"secret": func(name string) (string, error) {
+			return Secret("", name, "")
+		},
+	}
+}
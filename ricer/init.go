@@ -0,0 +1,46 @@
+package ricer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const defaultConfig = `# ricer configuration
+#
+# Each template is configured under its path relative to templates/
+# (without the .tmpl extension), one nested key per directory level, so
+# templates/nvim/init.lua.tmpl is configured under nvim: init.lua:. Set
+# "output" to where it should be rendered and "vars" to the values passed
+# to the template, e.g. for templates/myapp.tmpl:
+#
+# myapp:
+#   output: /home/user/.config/myapp/config
+#   vars:
+#     theme: dark
+`
+
+// Init scaffolds a default config.yaml and templates directory for
+// first-time use.
+func Init() error {
+	configHome, err := ConfigHomeDirectory()
+	if err != nil {
+		return err
+	}
+
+	tmplDir, err := TemplatesDirectory()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(tmplDir, os.ModePerm); err != nil {
+		return fmt.Errorf("Could not create %s: %s", tmplDir, err)
+	}
+
+	configFile := filepath.Join(configHome, "config.yaml")
+	if _, err := os.Stat(configFile); err == nil {
+		return fmt.Errorf("%s already exists", configFile)
+	}
+
+	return os.WriteFile(configFile, []byte(defaultConfig), 0644)
+}
@@ -0,0 +1,32 @@
+package ricer
+
+// TemplateInfo describes one discovered file, as reported by `ricer list`.
+type TemplateInfo struct {
+	Name   string
+	Source string
+	Output string
+}
+
+// List returns a TemplateInfo for every file discovered under tmplDir.
+func List(tmplDir string) ([]TemplateInfo, error) {
+	files, err := Discover(tmplDir)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]TemplateInfo, 0, len(files))
+	for _, f := range files {
+		output, err := OutputPath(f.RelPath)
+		if err != nil {
+			output = ""
+		}
+
+		infos = append(infos, TemplateInfo{
+			Name:   f.RelPath,
+			Source: f.Source,
+			Output: output,
+		})
+	}
+
+	return infos, nil
+}
@@ -0,0 +1,85 @@
+package ricer
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// activeProfile is the currently selected profile, set via SetProfile. An
+// empty string means no profile overlay is applied.
+var activeProfile string
+
+// SetProfile sets the active profile, whose `profiles.<name>.<tmpl>`
+// settings overlay the base configuration for every subsequent lookup.
+func SetProfile(name string) {
+	activeProfile = name
+}
+
+// Profile returns the active profile, or "" if none is selected.
+func Profile() string {
+	return activeProfile
+}
+
+// Profiles returns the names declared under the top-level `profiles`
+// config key, sorted.
+func Profiles() []string {
+	m, ok := viper.Get("profiles").(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// profileConfig returns the active profile's configuration overlay for
+// relPath, or nil if no profile is active or it declares none.
+func profileConfig(relPath string) map[string]interface{} {
+	if activeProfile == "" {
+		return nil
+	}
+
+	profiles, ok := viper.Get("profiles").(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	root, ok := profiles[activeProfile].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	return lookupRest(root, strings.Split(filepath.ToSlash(relPath), "/"))
+}
+
+// defaultVars returns the top-level variables every template can rely on
+// regardless of its own `vars`: the active profile, hostname, OS/arch, and
+// current user — the building blocks for a template that conditionally
+// renders blocks per host.
+func defaultVars() map[string]interface{} {
+	hostname, _ := os.Hostname()
+
+	username := ""
+	if u, err := user.Current(); err == nil {
+		username = u.Username
+	}
+
+	return map[string]interface{}{
+		"Profile": Profile(),
+		"Host":    hostname,
+		"OS":      runtime.GOOS,
+		"Arch":    runtime.GOARCH,
+		"User":    username,
+	}
+}
@@ -0,0 +1,167 @@
+package ricer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// defaultExecTimeout bounds how long an `exec` provider or the `exec`
+// template func may run before being killed.
+const defaultExecTimeout = 10 * time.Second
+
+// Provider describes one entry in a template's `providers` config list,
+// which supplies an additional value for the template's "dot" beyond what's
+// declared under `vars`.
+type Provider struct {
+	Type string `mapstructure:"type"`
+	// As is the key the resolved value is exposed under in the template's
+	// dot. Required.
+	As string `mapstructure:"as"`
+
+	// Key is the environment variable to read, for type "env".
+	Key string `mapstructure:"key"`
+	// Path is the file to read, for type "file", or to decrypt, for a
+	// "secret" provider using the "gpg" backend.
+	Path string `mapstructure:"path"`
+	// Command is the shell command to run, for type "exec".
+	Command string `mapstructure:"command"`
+	// Timeout bounds how long an "exec" provider may run. Defaults to
+	// defaultExecTimeout.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Name is the secret's name, for type "secret".
+	Name string `mapstructure:"name"`
+	// Backend selects the secret store to use, for type "secret". One of
+	// "pass" (default) or "gpg".
+	Backend string `mapstructure:"backend"`
+}
+
+// decodeProviders decodes raw (a `providers` config list) into out, with a
+// hook so timeouts can be written as duration strings (e.g. "5s") instead of
+// raw nanosecond integers.
+func decodeProviders(raw interface{}, out *[]Provider) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+		Result:     out,
+	})
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(raw)
+}
+
+// Resolve returns the value this provider supplies.
+func (p Provider) Resolve() (string, error) {
+	switch p.Type {
+	case "env":
+		return EnvVar(p.Key), nil
+	case "file":
+		return IncludeFile(p.Path)
+	case "exec":
+		timeout := p.Timeout
+		if timeout == 0 {
+			timeout = defaultExecTimeout
+		}
+		return Exec(p.Command, timeout)
+	case "secret":
+		return Secret(p.Backend, p.Name, p.Path)
+	default:
+		return "", fmt.Errorf("unknown provider type %q", p.Type)
+	}
+}
+
+// Vars assembles the "dot" passed to a template's Execute: defaultVars,
+// then the static values under <tmplName>.vars (or, under the active
+// profile, `profiles.<profile>.<tmplName>.vars` instead), then whatever
+// <tmplName>.providers resolve to — each layer free to override the last.
+func Vars(tmplName string) (map[string]interface{}, error) {
+	cfg := templateConfig(tmplName)
+
+	dot := defaultVars()
+
+	vars, _ := cfg["vars"].(map[string]interface{})
+	for k, v := range vars {
+		dot[k] = v
+	}
+
+	var providers []Provider
+	if raw, ok := cfg["providers"]; ok {
+		if err := decodeProviders(raw, &providers); err != nil {
+			return nil, fmt.Errorf("Could not read providers for template %s: %s", tmplName, err)
+		}
+	}
+
+	for _, p := range providers {
+		value, err := p.Resolve()
+		if err != nil {
+			return nil, fmt.Errorf("Could not resolve %s provider %q for template %s: %s", p.Type, p.As, tmplName, err)
+		}
+		dot[p.As] = value
+	}
+
+	return dot, nil
+}
+
+// EnvVar returns the value of the named environment variable, or "" if
+// it's unset. Only variables a template or config explicitly names are
+// ever exposed.
+func EnvVar(key string) string {
+	return os.Getenv(key)
+}
+
+// IncludeFile returns the contents of path as a string, expanding a
+// leading "~" to the current user's home directory.
+func IncludeFile(path string) (string, error) {
+	path, err := expandHome(path)
+	if err != nil {
+		return "", err
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("Could not read %s: %s", path, err)
+	}
+
+	return string(contents), nil
+}
+
+// Exec runs command through the shell and returns its trimmed stdout. It
+// is killed if it doesn't complete within timeout.
+func Exec(command string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("Could not run %q: %s", command, err)
+	}
+
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+// Secret resolves a value from an external secret store via the registered
+// SecretBackend named by backend (default "pass"); name is the pass entry
+// name, and path is the file to decrypt when backend is "gpg". See secret.go.
+func Secret(backend, name, path string) (string, error) {
+	if backend == "" {
+		backend = "pass"
+	}
+
+	b, ok := secretBackends[backend]
+	if !ok {
+		return "", fmt.Errorf("unknown secret backend %q", backend)
+	}
+
+	return b.Resolve(name, path)
+}
@@ -0,0 +1,29 @@
+package ricer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecodeProvidersTimeout(t *testing.T) {
+	raw := []interface{}{
+		map[string]interface{}{
+			"type":    "exec",
+			"as":      "out",
+			"command": "echo hi",
+			"timeout": "5s",
+		},
+	}
+
+	var providers []Provider
+	if err := decodeProviders(raw, &providers); err != nil {
+		t.Fatalf("decodeProviders: %v", err)
+	}
+
+	if len(providers) != 1 {
+		t.Fatalf("len(providers) = %d, want 1", len(providers))
+	}
+	if want := 5 * time.Second; providers[0].Timeout != want {
+		t.Errorf("Timeout = %v, want %v", providers[0].Timeout, want)
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Ricer generates configuration files based on templates.
+Copyright (C) 2016  Kristof Vannotten
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Package ricer holds the core logic for locating ricer's configuration and
+// templates and rendering them. The cobra commands in cmd/ are thin
+// wrappers around the functions in this package.
+package ricer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"sync"
+	"text/template"
+
+	"github.com/spf13/viper"
+)
+
+// ParseConfiguration loads ricer's viper configuration, either from
+// configFile or, if empty, from the default config home.
+func ParseConfiguration(configFile string) error {
+	if configFile == "" {
+		viper.SetConfigName("config")
+		configHome, err := ConfigHomeDirectory()
+		if err != nil {
+			return err
+		}
+		viper.AddConfigPath(configHome)
+	} else {
+		viper.SetConfigFile(configFile)
+	}
+
+	return viper.ReadInConfig()
+}
+
+// ConfigHomeDirectory returns the directory ricer's configuration lives in,
+// honoring XDG_CONFIG_HOME.
+func ConfigHomeDirectory() (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+
+	if configHome == "" {
+		homeDir := os.Getenv("HOME")
+		if homeDir == "" {
+			usr, err := user.Current()
+			if err != nil {
+				return "", err
+			}
+			homeDir = usr.HomeDir
+		}
+		configHome = path.Join(homeDir, "/.config")
+	}
+
+	return path.Join(configHome, "/ricer"), nil
+}
+
+// TemplatesDirectory returns the directory ricer discovers templates and
+// assets in.
+func TemplatesDirectory() (string, error) {
+	config, err := ConfigHomeDirectory()
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(config, "/templates"), nil
+}
+
+// OutputPath returns where the file whose configuration key is relPath
+// should be written: its own `output` setting if one is configured,
+// otherwise relPath resolved against the top-level `output_root`.
+func OutputPath(relPath string) (string, error) {
+	if out := templateString(relPath, "output"); out != "" {
+		return expandHome(out)
+	}
+
+	root := viper.GetString("output_root")
+	if root == "" {
+		return "", fmt.Errorf("You have to define output_root or an explicit output for %s", relPath)
+	}
+
+	root, err := expandHome(root)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, relPath), nil
+}
+
+// Apply renders or copies every file in files to its output, running up to
+// 4 at a time.
+func Apply(files []File) {
+	var throttle = make(chan int, 4)
+	var wg sync.WaitGroup
+
+	for _, f := range files {
+		throttle <- 1
+		wg.Add(1)
+
+		go func(f File) {
+			defer wg.Done()
+
+			var err error
+			if f.Template {
+				err = HandleTemplate(f)
+			} else {
+				_, err = Copy(f)
+			}
+			if err != nil {
+				fmt.Println(err)
+			}
+
+			<-throttle
+		}(f)
+	}
+
+	wg.Wait()
+}
+
+// renderTemplate parses and executes f's source, returning the rendered
+// bytes along with its configured output path.
+func renderTemplate(f File) (*bytes.Buffer, string, error) {
+	t, err := template.New(filepath.Base(f.Source)).Funcs(templateFuncs()).ParseFiles(f.Source)
+	if err != nil {
+		return nil, "", fmt.Errorf("Could not parse template %s", f.Source)
+	}
+
+	outputFile, err := OutputPath(f.RelPath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	dot, err := Vars(f.RelPath)
+	if err != nil {
+		return nil, outputFile, err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, dot); err != nil {
+		return nil, outputFile, fmt.Errorf("Could not render template %s: %s", f.RelPath, err)
+	}
+
+	return &buf, outputFile, nil
+}
+
+// HandleTemplate renders f and, unless the result is byte-for-byte
+// identical to what's already there, atomically writes it to its
+// configured output, running that template's pre_hook/post_hook around
+// the write.
+func HandleTemplate(f File) error {
+	buf, outputFile, err := renderTemplate(f)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputFile), os.ModePerm); err != nil {
+		return fmt.Errorf("[1] Could not create %s for template %s", outputFile, f.RelPath)
+	}
+
+	if existing, err := os.ReadFile(outputFile); err == nil && bytes.Equal(existing, buf.Bytes()) {
+		return nil
+	}
+
+	if hook := templateString(f.RelPath, "pre_hook"); hook != "" {
+		if err := runHook(hook, outputFile); err != nil {
+			return fmt.Errorf("pre_hook for template %s failed: %s", f.RelPath, err)
+		}
+	}
+
+	mode, err := outputMode(f.RelPath, outputFile, 0644)
+	if err != nil {
+		return err
+	}
+
+	if err := writeAtomic(outputFile, buf.Bytes(), mode, f.RelPath); err != nil {
+		return err
+	}
+
+	fmt.Printf("Creating %s from template %s.\n", outputFile, f.RelPath)
+
+	if hook := templateString(f.RelPath, "post_hook"); hook != "" {
+		if err := runHook(hook, outputFile); err != nil {
+			return fmt.Errorf("post_hook for template %s failed: %s", f.RelPath, err)
+		}
+	}
+
+	return nil
+}
+
+// Copy copies f's source file to its output path verbatim, and skips the
+// write if the destination already has identical contents. The output gets
+// the configured `mode` if there is one, else an existing output file's
+// current permissions, else the source file's permissions.
+func Copy(f File) (string, error) {
+	outputFile, err := OutputPath(f.RelPath)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(f.Source)
+	if err != nil {
+		return "", fmt.Errorf("Could not read %s: %s", f.Source, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputFile), os.ModePerm); err != nil {
+		return "", fmt.Errorf("Could not create %s for %s", outputFile, f.RelPath)
+	}
+
+	if existing, err := os.ReadFile(outputFile); err == nil && bytes.Equal(existing, data) {
+		return outputFile, nil
+	}
+
+	srcInfo, err := os.Stat(f.Source)
+	if err != nil {
+		return "", err
+	}
+
+	mode, err := outputMode(f.RelPath, outputFile, srcInfo.Mode().Perm())
+	if err != nil {
+		return "", err
+	}
+
+	if err := writeAtomic(outputFile, data, mode, f.RelPath); err != nil {
+		return "", err
+	}
+
+	fmt.Printf("Copying %s to %s.\n", f.Source, outputFile)
+
+	return outputFile, nil
+}
@@ -0,0 +1,61 @@
+package ricer
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// SecretBackend resolves a named secret from some external store. New
+// backends register themselves with RegisterSecretBackend instead of
+// Secret growing another branch to dispatch to.
+type SecretBackend interface {
+	// Resolve returns the secret's value. name is the secret's name (for a
+	// "pass"-like backend); path is a file to decrypt (for a "gpg"-like
+	// backend) — a given backend uses whichever one applies to it.
+	Resolve(name, path string) (string, error)
+}
+
+// secretBackends holds every registered SecretBackend, keyed by the name
+// used in a template's `backend` config. The `secret` template func always
+// uses the "pass" entry.
+var secretBackends = map[string]SecretBackend{}
+
+// RegisterSecretBackend makes backend available under name for the
+// "secret" provider type. The `secret` template func does not select a
+// backend and always resolves through "pass".
+func RegisterSecretBackend(name string, backend SecretBackend) {
+	secretBackends[name] = backend
+}
+
+func init() {
+	RegisterSecretBackend("pass", passBackend{})
+	RegisterSecretBackend("gpg", gpgBackend{})
+}
+
+// passBackend resolves secrets by name from the `pass` password manager.
+type passBackend struct{}
+
+func (passBackend) Resolve(name, _ string) (string, error) {
+	out, err := exec.Command("pass", "show", name).Output()
+	if err != nil {
+		return "", fmt.Errorf("Could not read secret %q from pass: %s", name, err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// gpgBackend resolves secrets by gpg-decrypting the file at path.
+type gpgBackend struct{}
+
+func (gpgBackend) Resolve(_, path string) (string, error) {
+	expanded, err := expandHome(path)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := exec.Command("gpg", "--quiet", "--decrypt", expanded).Output()
+	if err != nil {
+		return "", fmt.Errorf("Could not decrypt %s with gpg: %s", expanded, err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
@@ -0,0 +1,30 @@
+package ricer
+
+import "testing"
+
+type stubSecretBackend struct {
+	value string
+}
+
+func (s stubSecretBackend) Resolve(name, path string) (string, error) {
+	return s.value, nil
+}
+
+func TestSecretDispatchesToRegisteredBackend(t *testing.T) {
+	RegisterSecretBackend("stub", stubSecretBackend{value: "stub-value"})
+	defer delete(secretBackends, "stub")
+
+	got, err := Secret("stub", "whatever", "")
+	if err != nil {
+		t.Fatalf("Secret: %v", err)
+	}
+	if got != "stub-value" {
+		t.Errorf("Secret() = %q, want %q", got, "stub-value")
+	}
+}
+
+func TestSecretUnknownBackend(t *testing.T) {
+	if _, err := Secret("not-a-real-backend", "whatever", ""); err == nil {
+		t.Error("Secret() with an unregistered backend returned no error")
+	}
+}
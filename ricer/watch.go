@@ -0,0 +1,180 @@
+/*
+Ricer generates configuration files based on templates.
+Copyright (C) 2016  Kristof Vannotten
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package ricer
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// watchDebounce is how long we wait after the last event for a given file
+// before re-rendering it, so that editors which write a file in several
+// small steps only trigger a single re-render.
+const watchDebounce = 300 * time.Millisecond
+
+// Watch keeps ricer running, re-rendering files under tmplDir (recursively)
+// whenever their source changes and re-rendering whichever files are
+// affected whenever the viper config file changes.
+func Watch(tmplDir string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("Could not start watcher: %s", err)
+	}
+	defer watcher.Close()
+
+	if err := addDirsRecursively(watcher, tmplDir); err != nil {
+		return fmt.Errorf("Could not watch %s: %s", tmplDir, err)
+	}
+
+	lastConfigs := snapshotConfigs(tmplDir)
+	viper.OnConfigChange(func(e fsnotify.Event) {
+		lastConfigs = handleConfigChange(tmplDir, lastConfigs)
+	})
+	viper.WatchConfig()
+
+	fmt.Printf("Watching %s for changes (Ctrl+C to stop).\n", tmplDir)
+
+	timers := make(map[string]*time.Timer)
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+
+			file := event.Name
+			if t, ok := timers[file]; ok {
+				t.Stop()
+			}
+			timers[file] = time.AfterFunc(watchDebounce, func() {
+				handleSourceChange(tmplDir, file)
+			})
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Println(err)
+		}
+	}
+}
+
+// addDirsRecursively adds tmplDir and every (non-skipped) subdirectory
+// under it to watcher, since fsnotify only watches one directory level at
+// a time.
+func addDirsRecursively(watcher *fsnotify.Watcher, tmplDir string) error {
+	dirs, err := discoverDirs(tmplDir)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handleSourceChange re-renders the single file that changed on disk, if
+// it's still one Discover would pick up.
+func handleSourceChange(tmplDir, file string) {
+	files, err := Discover(tmplDir)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, f := range files {
+		if f.Source != file {
+			continue
+		}
+
+		var err error
+		if f.Template {
+			err = HandleTemplate(f)
+		} else {
+			_, err = Copy(f)
+		}
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+// snapshotConfigs returns every discovered file's current configuration
+// section, keyed by RelPath, to compare against after a future config
+// change.
+func snapshotConfigs(tmplDir string) map[string]map[string]interface{} {
+	files, err := Discover(tmplDir)
+	if err != nil {
+		fmt.Println(err)
+		return nil
+	}
+
+	configs := make(map[string]map[string]interface{}, len(files))
+	for _, f := range files {
+		configs[f.RelPath] = templateConfig(f.RelPath)
+	}
+
+	return configs
+}
+
+// handleConfigChange re-renders only the files whose configuration changed
+// since lastConfigs, and returns the current configs to compare against
+// next time.
+func handleConfigChange(tmplDir string, lastConfigs map[string]map[string]interface{}) map[string]map[string]interface{} {
+	files, err := Discover(tmplDir)
+	if err != nil {
+		fmt.Println(err)
+		return lastConfigs
+	}
+
+	configs := make(map[string]map[string]interface{}, len(files))
+	for _, f := range files {
+		cfg := templateConfig(f.RelPath)
+		configs[f.RelPath] = cfg
+
+		if reflect.DeepEqual(lastConfigs[f.RelPath], cfg) {
+			continue
+		}
+
+		fmt.Printf("Configuration for %s changed, re-rendering.\n", f.RelPath)
+
+		var err error
+		if f.Template {
+			err = HandleTemplate(f)
+		} else {
+			_, err = Copy(f)
+		}
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	return configs
+}
@@ -0,0 +1,112 @@
+package ricer
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+)
+
+// writeAtomic writes data to a temp file in outputFile's directory, applies
+// mode and tmplName's configured owner/group, and renames it into place,
+// so a failed or half-written render can never leave outputFile truncated.
+func writeAtomic(outputFile string, data []byte, mode os.FileMode, tmplName string) error {
+	dir := filepath.Dir(outputFile)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(outputFile)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("[2] Could not create %s for template %s", outputFile, tmplName)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // no-op once the rename below succeeds
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Could not write %s for template %s: %s", outputFile, tmplName, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("Could not write %s for template %s: %s", outputFile, tmplName, err)
+	}
+
+	if err := os.Chmod(tmpName, mode); err != nil {
+		return fmt.Errorf("Could not set permissions on %s for template %s: %s", outputFile, tmplName, err)
+	}
+
+	if err := chown(tmpName, tmplName); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpName, outputFile); err != nil {
+		return fmt.Errorf("Could not move %s into place for template %s: %s", outputFile, tmplName, err)
+	}
+
+	return nil
+}
+
+// outputMode decides the permissions to write outputFile with: tmplName's
+// configured `mode` (octal, e.g. "0644") if set, otherwise outputFile's
+// current permissions if it already exists, otherwise fallback.
+func outputMode(tmplName, outputFile string, fallback os.FileMode) (os.FileMode, error) {
+	if modeStr := templateString(tmplName, "mode"); modeStr != "" {
+		parsed, err := strconv.ParseUint(modeStr, 8, 32)
+		if err != nil {
+			return 0, fmt.Errorf("Invalid mode %q for template %s: %s", modeStr, tmplName, err)
+		}
+		return os.FileMode(parsed), nil
+	}
+
+	if info, err := os.Stat(outputFile); err == nil {
+		return info.Mode().Perm(), nil
+	}
+
+	return fallback, nil
+}
+
+// chown applies tmplName's configured `owner`/`group`, if any, to path.
+func chown(path, tmplName string) error {
+	ownerName := templateString(tmplName, "owner")
+	groupName := templateString(tmplName, "group")
+	if ownerName == "" && groupName == "" {
+		return nil
+	}
+
+	uid := -1
+	if ownerName != "" {
+		u, err := user.Lookup(ownerName)
+		if err != nil {
+			return fmt.Errorf("Could not look up owner %q for template %s: %s", ownerName, tmplName, err)
+		}
+		if uid, err = strconv.Atoi(u.Uid); err != nil {
+			return err
+		}
+	}
+
+	gid := -1
+	if groupName != "" {
+		g, err := user.LookupGroup(groupName)
+		if err != nil {
+			return fmt.Errorf("Could not look up group %q for template %s: %s", groupName, tmplName, err)
+		}
+		if gid, err = strconv.Atoi(g.Gid); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Chown(path, uid, gid); err != nil {
+		return fmt.Errorf("Could not chown %s for template %s: %s", path, tmplName, err)
+	}
+
+	return nil
+}
+
+// runHook runs command through the shell, exposing the template's output
+// path to it via the OUTPUT environment variable.
+func runHook(command, outputFile string) error {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("OUTPUT=%s", outputFile))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
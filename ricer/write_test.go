@@ -0,0 +1,119 @@
+package ricer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestOutputMode(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	dir := t.TempDir()
+
+	config := map[string]interface{}{
+		"withmode": map[string]interface{}{"mode": "0600"},
+	}
+	if err := viper.MergeConfigMap(config); err != nil {
+		t.Fatalf("MergeConfigMap: %v", err)
+	}
+
+	existing := filepath.Join(dir, "existing")
+	if err := os.WriteFile(existing, []byte("x"), 0o640); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		tmplName   string
+		outputFile string
+		fallback   os.FileMode
+		want       os.FileMode
+	}{
+		{
+			name:       "explicit mode wins",
+			tmplName:   "withmode",
+			outputFile: filepath.Join(dir, "does-not-exist"),
+			fallback:   0o644,
+			want:       0o600,
+		},
+		{
+			name:       "no explicit mode preserves existing file's permissions",
+			tmplName:   "nomode",
+			outputFile: existing,
+			fallback:   0o644,
+			want:       0o640,
+		},
+		{
+			name:       "no explicit mode, no existing file, falls back",
+			tmplName:   "nomode",
+			outputFile: filepath.Join(dir, "does-not-exist"),
+			fallback:   0o644,
+			want:       0o644,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := outputMode(tt.tmplName, tt.outputFile, tt.fallback)
+			if err != nil {
+				t.Fatalf("outputMode: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("outputMode() = %o, want %o", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputModeInvalid(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	if err := viper.MergeConfigMap(map[string]interface{}{
+		"badmode": map[string]interface{}{"mode": "not-octal"},
+	}); err != nil {
+		t.Fatalf("MergeConfigMap: %v", err)
+	}
+
+	if _, err := outputMode("badmode", filepath.Join(t.TempDir(), "out"), 0o644); err == nil {
+		t.Error("outputMode() with an invalid mode string returned no error")
+	}
+}
+
+func TestWriteAtomicAppliesMode(t *testing.T) {
+	dir := t.TempDir()
+	outputFile := filepath.Join(dir, "out")
+
+	if err := writeAtomic(outputFile, []byte("hello"), 0o600, "sometmpl"); err != nil {
+		t.Fatalf("writeAtomic: %v", err)
+	}
+
+	got, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(got) != "hello" {
+		t.Errorf("contents = %q, want %q", got, "hello")
+	}
+
+	info, err := os.Stat(outputFile)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %o, want %o", info.Mode().Perm(), 0o600)
+	}
+
+	// No leftover temp file once the rename has succeeded.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("dir has %d entries after writeAtomic, want 1: %v", len(entries), entries)
+	}
+}